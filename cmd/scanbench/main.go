@@ -0,0 +1,64 @@
+// Command scanbench compares the concurrent scanner against a plain
+// filepath.Walk baseline on a real directory tree, so regressions in
+// --scan-concurrency tuning show up as numbers instead of vibes.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/luckylittle/sizechecker/scanner"
+)
+
+func walkBaseline(root string) (int64, error) {
+	var size int64
+	err := filepath.Walk(root, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+func main() {
+	rootFlag := flag.String("root", ".", "Directory tree to scan")
+	concurrencyFlag := flag.Int("scan-concurrency", 0, "Worker count for the concurrent scanner (0 = runtime.NumCPU())")
+	flag.Parse()
+
+	root, err := filepath.Abs(*rootFlag)
+	if err != nil {
+		fmt.Printf("Error resolving root: %v\n", err)
+		os.Exit(2)
+	}
+
+	start := time.Now()
+	baselineSize, err := walkBaseline(root)
+	if err != nil {
+		fmt.Printf("Error during filepath.Walk baseline: %v\n", err)
+		os.Exit(2)
+	}
+	baselineElapsed := time.Since(start)
+
+	start = time.Now()
+	concurrentSize, err := scanner.Walk(root, scanner.Options{Concurrency: *concurrencyFlag})
+	if err != nil {
+		fmt.Printf("Error during concurrent scan: %v\n", err)
+		os.Exit(2)
+	}
+	concurrentElapsed := time.Since(start)
+
+	fmt.Printf("filepath.Walk:     %10d bytes in %s\n", baselineSize, baselineElapsed)
+	fmt.Printf("scanner.Walk:      %10d bytes in %s\n", concurrentSize, concurrentElapsed)
+
+	if baselineSize != concurrentSize {
+		fmt.Printf("Warning: size mismatch between the two scans (%d vs %d) - results may have changed mid-scan.\n",
+			baselineSize, concurrentSize)
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/inhies/go-bytesize"
+	"github.com/luckylittle/sizechecker/notifier"
+	"github.com/luckylittle/sizechecker/scanner"
+)
+
+// parsePercent turns "80%" (or "80") into the fraction 0.8, for the
+// --warn/--crit hysteresis band flags.
+func parsePercent(s string) (float64, error) {
+	s = strings.TrimSpace(strings.TrimSuffix(s, "%"))
+	if s == "" {
+		return 0, nil
+	}
+	pct, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing percentage %q: %v", s, err)
+	}
+	return pct / 100, nil
+}
+
+// daemonState tracks the last scan outcome so --daemon can tell a fresh
+// breach from a flapping one, and so /healthz can tell a stalled scanner
+// from a healthy one.
+type daemonState struct {
+	mu          sync.Mutex
+	breached    bool
+	everChecked bool
+
+	lastScanOK   atomic.Bool
+	lastScanUnix atomic.Int64
+}
+
+func newDaemonState() *daemonState {
+	return &daemonState{}
+}
+
+// transition reports whether breached differs from the last recorded state
+// (a state *change*, not the state itself), and records the new state. The
+// very first call always reports a transition, so a daemon that starts up
+// already breached sends its initial notification instead of staying silent
+// until the next flip.
+func (d *daemonState) transition(breached bool) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	changed := !d.everChecked || breached != d.breached
+	d.breached = breached
+	d.everChecked = true
+	return changed
+}
+
+func (d *daemonState) isBreached() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.breached
+}
+
+func (d *daemonState) recordScan(ok bool) {
+	d.lastScanOK.Store(ok)
+	d.lastScanUnix.Store(time.Now().Unix())
+}
+
+// registerHealthHandlers adds /healthz and /readyz to mux. /healthz fails
+// when the most recent scan failed, or when it is older than 2x interval,
+// which is the signal something has wedged rather than just being between
+// ticks.
+func (d *daemonState) registerHealthHandlers(mux *http.ServeMux, interval time.Duration) {
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
+		last := d.lastScanUnix.Load()
+		if last == 0 || !d.lastScanOK.Load() || time.Since(time.Unix(last, 0)) > 2*interval {
+			http.Error(w, "unhealthy: no recent successful scan", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, _ *http.Request) {
+		if d.lastScanUnix.Load() == 0 {
+			http.Error(w, "not ready: no scan has completed yet", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+}
+
+// hysteresisBreach applies --warn/--crit bands on top of a raw breach
+// signal so a value oscillating around the limit doesn't flip the alert
+// state every tick. Below warn it's never breached, at/above crit it's
+// always breached; in between, it holds whatever the previous state was.
+func hysteresisBreach(wasBreached bool, fraction, warn, crit float64) bool {
+	if fraction >= crit {
+		return true
+	}
+	if fraction < warn {
+		return false
+	}
+	return wasBreached
+}
+
+// useHysteresis reports whether --warn/--crit hysteresis should apply.
+// Hysteresis needs both bands: with only one set, the unset one defaults to
+// 0, and a fraction is never below a warn of 0 and always at or above a crit
+// of 0, so a breach could never go back to recovered.
+func useHysteresis(warn, crit float64) bool {
+	return warn > 0 && crit > 0
+}
+
+// runDaemon keeps sizechecker alive, re-checking absDir on a ticker and
+// notifying only on OK<->breach transitions. It never returns; the health
+// endpoints, served on metricsAddr, are how operators supervise it.
+func runDaemon(absDir, runType string, limitBytes bytesize.ByteSize, rawLimit, hostname, units, format string, warn, crit float64, interval time.Duration, targets []notifier.Target, limiter *notifier.RateLimiter, scanOpts scanner.Options, state *daemonState) {
+	firstTransition := true
+	tick := func() {
+		// --daemon always serves /metrics (startMetricsServer is called
+		// unconditionally in main()), so runCheck's used-bytes scan for the
+		// 'a' runtype is never wasted work here.
+		exitCode, msg := runCheck(absDir, runType, limitBytes, rawLimit, hostname, units, format, nil, limiter, scanOpts, true)
+		state.recordScan(exitCode != 2)
+		if exitCode == 2 {
+			return
+		}
+
+		rawBreach := exitCode == 1
+		breached := rawBreach
+		if useHysteresis(warn, crit) {
+			fraction, ok := breachFraction(runType, msg)
+			if ok {
+				breached = hysteresisBreach(state.isBreached(), fraction, warn, crit)
+			}
+		}
+
+		if !state.transition(breached) {
+			return
+		}
+		// Starting up within limits is not a breach -> OK change, so the
+		// first recorded state only notifies when it is a breach.
+		wasFirst := firstTransition
+		firstTransition = false
+		if wasFirst && !breached {
+			return
+		}
+
+		if breached {
+			fmt.Printf("State transition: OK -> breach for %s.\n", absDir)
+			sendNotifications(targets, limiter, notifier.Message{
+				Directory: absDir, RunType: runType, Hostname: hostname,
+				Severity: "warning", Breached: true,
+				Text: fmt.Sprintf("sizechecker: %s crossed into breach on %s.", absDir, hostname),
+			})
+		} else {
+			fmt.Printf("State transition: breach -> OK for %s.\n", absDir)
+			sendNotifications(targets, limiter, notifier.Message{
+				Directory: absDir, RunType: runType, Hostname: hostname,
+				Severity: "ok", Breached: false,
+				Text: fmt.Sprintf("sizechecker: %s has recovered on %s.", absDir, hostname),
+			})
+		}
+	}
+
+	tick()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		tick()
+	}
+}
+
+// breachFraction derives how far the tick's scan got through its limit, as
+// a fraction, so runDaemon can apply the --warn/--crit hysteresis bands. It
+// reuses the notifier.Message runCheck already computed instead of
+// re-scanning absDir a second time per tick, which would both double the
+// scan cost and risk a "used" figure that disagrees with the one runCheck
+// just reported if the directory changed between the two scans.
+func breachFraction(runType string, msg notifier.Message) (float64, bool) {
+	switch runType {
+	case "u", "i":
+		if msg.LimitBytes == 0 {
+			return 0, false
+		}
+		return float64(msg.UsedBytes) / float64(msg.LimitBytes), true
+	case "a":
+		if msg.AvailableBytes == 0 {
+			return 0, false
+		}
+		return float64(msg.LimitBytes) / float64(msg.AvailableBytes), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/luckylittle/sizechecker/notifier"
+)
+
+func TestParsePercent(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    float64
+		wantErr bool
+	}{
+		{in: "", want: 0},
+		{in: "80%", want: 0.8},
+		{in: "80", want: 0.8},
+		{in: "100%", want: 1},
+		{in: "not-a-number", wantErr: true},
+	}
+
+	for _, c := range cases {
+		got, err := parsePercent(c.in)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("parsePercent(%q): expected an error, got nil", c.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parsePercent(%q): unexpected error: %v", c.in, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("parsePercent(%q) = %v, want %v", c.in, got, c.want)
+		}
+	}
+}
+
+func TestHysteresisBreach(t *testing.T) {
+	cases := []struct {
+		name        string
+		wasBreached bool
+		fraction    float64
+		warn, crit  float64
+		want        bool
+	}{
+		{name: "below warn, was ok", wasBreached: false, fraction: 0.5, warn: 0.8, crit: 0.95, want: false},
+		{name: "below warn, was breached", wasBreached: true, fraction: 0.5, warn: 0.8, crit: 0.95, want: false},
+		{name: "at or above crit, was ok", wasBreached: false, fraction: 0.96, warn: 0.8, crit: 0.95, want: true},
+		{name: "between warn and crit, was ok", wasBreached: false, fraction: 0.85, warn: 0.8, crit: 0.95, want: false},
+		{name: "between warn and crit, was breached", wasBreached: true, fraction: 0.85, warn: 0.8, crit: 0.95, want: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := hysteresisBreach(c.wasBreached, c.fraction, c.warn, c.crit)
+			if got != c.want {
+				t.Errorf("hysteresisBreach(%v, %v, %v, %v) = %v, want %v",
+					c.wasBreached, c.fraction, c.warn, c.crit, got, c.want)
+			}
+		})
+	}
+}
+
+func TestUseHysteresis(t *testing.T) {
+	cases := []struct {
+		name       string
+		warn, crit float64
+		want       bool
+	}{
+		{name: "both set", warn: 0.8, crit: 0.95, want: true},
+		{name: "only crit set", warn: 0, crit: 0.95, want: false},
+		{name: "only warn set", warn: 0.8, crit: 0, want: false},
+		{name: "neither set", warn: 0, crit: 0, want: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := useHysteresis(c.warn, c.crit); got != c.want {
+				t.Errorf("useHysteresis(%v, %v) = %v, want %v", c.warn, c.crit, got, c.want)
+			}
+		})
+	}
+}
+
+func TestDaemonStateTransition(t *testing.T) {
+	d := newDaemonState()
+
+	if !d.transition(false) {
+		t.Error("first call should always report a transition")
+	}
+	if d.isBreached() {
+		t.Error("state should be OK after transition(false)")
+	}
+
+	if !d.transition(true) {
+		t.Error("flipping OK -> breach should report a transition")
+	}
+	if !d.isBreached() {
+		t.Error("state should be breached after transition(true)")
+	}
+
+	if d.transition(true) {
+		t.Error("repeating the same state should not report a transition")
+	}
+
+	if !d.transition(false) {
+		t.Error("flipping breach -> OK should report a transition")
+	}
+}
+
+func TestBreachFraction(t *testing.T) {
+	cases := []struct {
+		name    string
+		runType string
+		msg     notifier.Message
+		want    float64
+		wantOK  bool
+	}{
+		{
+			name:    "used runtype",
+			runType: "u",
+			msg:     notifier.Message{UsedBytes: 80, LimitBytes: 100},
+			want:    0.8, wantOK: true,
+		},
+		{
+			name:    "used runtype, zero limit",
+			runType: "u",
+			msg:     notifier.Message{UsedBytes: 80, LimitBytes: 0},
+			want:    0, wantOK: false,
+		},
+		{
+			name:    "available runtype",
+			runType: "a",
+			msg:     notifier.Message{LimitBytes: 50, AvailableBytes: 100},
+			want:    0.5, wantOK: true,
+		},
+		{
+			name:    "inode runtype",
+			runType: "i",
+			msg:     notifier.Message{UsedBytes: 95, LimitBytes: 100},
+			want:    0.95, wantOK: true,
+		},
+		{
+			name:    "unknown runtype",
+			runType: "x",
+			msg:     notifier.Message{UsedBytes: 80, LimitBytes: 100},
+			want:    0, wantOK: false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, ok := breachFraction(c.runType, c.msg)
+			if ok != c.wantOK {
+				t.Fatalf("breachFraction(%q, %+v) ok = %v, want %v", c.runType, c.msg, ok, c.wantOK)
+			}
+			if ok && got != c.want {
+				t.Errorf("breachFraction(%q, %+v) = %v, want %v", c.runType, c.msg, got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import "fmt"
+
+const (
+	unitsIEC = "iec"
+	unitsSI  = "si"
+	unitsRaw = "raw"
+
+	formatText = "text"
+	formatJSON = "json"
+)
+
+var iecUnitLabels = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+var siUnitLabels = []string{"B", "KB", "MB", "GB", "TB", "PB", "EB"}
+
+// formatBytes renders n bytes according to units: "iec" (1024-based,
+// GiB/MiB), "si" (1000-based, GB/MB), or "raw" (plain integer bytes).
+// Equivalent to choosing between humanize.IBytes and humanize.Bytes.
+func formatBytes(n int64, units string) string {
+	switch units {
+	case unitsSI:
+		return formatWithBase(n, 1000, siUnitLabels)
+	case unitsRaw:
+		return fmt.Sprintf("%d bytes", n)
+	default:
+		return formatWithBase(n, 1024, iecUnitLabels)
+	}
+}
+
+func formatWithBase(n int64, base float64, labels []string) string {
+	value := float64(n)
+	neg := value < 0
+	if neg {
+		value = -value
+	}
+
+	idx := 0
+	for value >= base && idx < len(labels)-1 {
+		value /= base
+		idx++
+	}
+	if neg {
+		value = -value
+	}
+
+	if idx == 0 {
+		return fmt.Sprintf("%d %s", n, labels[0])
+	}
+	return fmt.Sprintf("%.2f %s", value, labels[idx])
+}
@@ -0,0 +1,37 @@
+package main
+
+import "testing"
+
+func TestFormatBytes(t *testing.T) {
+	cases := []struct {
+		name  string
+		n     int64
+		units string
+		want  string
+	}{
+		{name: "iec bytes", n: 512, units: unitsIEC, want: "512 B"},
+		{name: "iec kib", n: 2048, units: unitsIEC, want: "2.00 KiB"},
+		{name: "iec gib", n: 5 * 1024 * 1024 * 1024, units: unitsIEC, want: "5.00 GiB"},
+		{name: "si kb", n: 2000, units: unitsSI, want: "2.00 KB"},
+		{name: "si gb", n: 5_000_000_000, units: unitsSI, want: "5.00 GB"},
+		{name: "raw", n: 123456, units: unitsRaw, want: "123456 bytes"},
+		{name: "unknown units default to iec", n: 2048, units: "bogus", want: "2.00 KiB"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := formatBytes(c.n, c.units)
+			if got != c.want {
+				t.Errorf("formatBytes(%d, %q) = %q, want %q", c.n, c.units, got, c.want)
+			}
+		})
+	}
+}
+
+func TestFormatWithBaseNegative(t *testing.T) {
+	got := formatWithBase(-2048, 1024, iecUnitLabels)
+	want := "-2.00 KiB"
+	if got != want {
+		t.Errorf("formatWithBase(-2048, 1024, iecUnitLabels) = %q, want %q", got, want)
+	}
+}
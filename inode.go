@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// getInodeStats returns the total and free inode counts for the filesystem
+// backing dir, read straight from Statfs_t the same way getAvailableSpace
+// reads Bavail/Bsize.
+func getInodeStats(dir string) (total uint64, free uint64, err error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, 0, err
+	}
+	return uint64(stat.Files), uint64(stat.Ffree), nil
+}
+
+// parseInodeLimit accepts either an absolute inode count (e.g. "100000") or
+// a percentage of total inodes (e.g. "95%"), returning the absolute used-inode
+// threshold that triggers a breach.
+func parseInodeLimit(limit string, totalInodes uint64) (uint64, error) {
+	limit = strings.TrimSpace(limit)
+	if strings.HasSuffix(limit, "%") {
+		pct, err := strconv.ParseFloat(strings.TrimSuffix(limit, "%"), 64)
+		if err != nil {
+			return 0, fmt.Errorf("error parsing inode limit percentage: %v", err)
+		}
+		if pct < 0 || pct > 100 {
+			return 0, fmt.Errorf("inode limit percentage must be between 0 and 100")
+		}
+		return uint64(pct / 100 * float64(totalInodes)), nil
+	}
+
+	count, err := strconv.ParseUint(limit, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing inode limit count: %v", err)
+	}
+	return count, nil
+}
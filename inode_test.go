@@ -0,0 +1,39 @@
+package main
+
+import "testing"
+
+func TestParseInodeLimit(t *testing.T) {
+	cases := []struct {
+		name        string
+		limit       string
+		totalInodes uint64
+		want        uint64
+		wantErr     bool
+	}{
+		{name: "absolute count", limit: "100000", totalInodes: 1000000, want: 100000},
+		{name: "percentage", limit: "95%", totalInodes: 1000000, want: 950000},
+		{name: "percentage with spaces", limit: " 50% ", totalInodes: 200, want: 100},
+		{name: "percentage over 100", limit: "150%", totalInodes: 1000, wantErr: true},
+		{name: "negative percentage", limit: "-5%", totalInodes: 1000, wantErr: true},
+		{name: "garbage percentage", limit: "abc%", totalInodes: 1000, wantErr: true},
+		{name: "garbage count", limit: "abc", totalInodes: 1000, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := parseInodeLimit(c.limit, c.totalInodes)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("parseInodeLimit(%q, %d): expected an error, got nil", c.limit, c.totalInodes)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseInodeLimit(%q, %d): unexpected error: %v", c.limit, c.totalInodes, err)
+			}
+			if got != c.want {
+				t.Errorf("parseInodeLimit(%q, %d) = %d, want %d", c.limit, c.totalInodes, got, c.want)
+			}
+		})
+	}
+}
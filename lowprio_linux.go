@@ -0,0 +1,39 @@
+//go:build linux
+
+package main
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// IOPRIO_WHO_PROCESS and IOPRIO_CLASS_IDLE/IOPRIO_CLASS_SHIFT come from
+// linux/ioprio.h. There's no Go stdlib or x/sys/unix wrapper for ioprio_set,
+// so the syscall is issued directly.
+const (
+	ioprioWhoProcess  = 1
+	ioprioClassIdle   = 3
+	ioprioClassShift  = 13
+	ioprioIdleDataLvl = 7
+)
+
+// lowerPriority drops this process to idle I/O priority and nice 19 CPU
+// priority, the same pair syncthing uses before a large background scan, so
+// it doesn't starve foreground workloads sharing the same host. On
+// architectures where ioprioSupported is false (sysIoprioSet hasn't been
+// pinned there), it falls back to nice alone.
+func lowerPriority() error {
+	if ioprioSupported {
+		prio := uintptr(ioprioClassIdle<<ioprioClassShift | ioprioIdleDataLvl)
+		if _, _, errno := unix.Syscall(sysIoprioSet, ioprioWhoProcess, 0, prio); errno != 0 {
+			return fmt.Errorf("ioprio_set: %v", errno)
+		}
+	}
+
+	if err := unix.Setpriority(unix.PRIO_PROCESS, 0, 19); err != nil {
+		return fmt.Errorf("setpriority: %v", err)
+	}
+
+	return nil
+}
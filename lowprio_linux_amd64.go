@@ -0,0 +1,9 @@
+//go:build linux && amd64
+
+package main
+
+// sysIoprioSet is SYS_ioprio_set on amd64. x/sys/unix doesn't export it, so
+// it's pinned here per-arch since the number differs across Linux ABIs.
+const sysIoprioSet = 251
+
+const ioprioSupported = true
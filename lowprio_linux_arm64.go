@@ -0,0 +1,9 @@
+//go:build linux && arm64
+
+package main
+
+// sysIoprioSet is SYS_ioprio_set on arm64. x/sys/unix doesn't export it, so
+// it's pinned here per-arch since the number differs across Linux ABIs.
+const sysIoprioSet = 30
+
+const ioprioSupported = true
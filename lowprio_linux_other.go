@@ -0,0 +1,11 @@
+//go:build linux && !amd64 && !arm64
+
+package main
+
+// sysIoprioSet is unpinned on Linux architectures other than amd64/arm64;
+// SYS_ioprio_set's number isn't in x/sys/unix and hasn't been verified here
+// for the rest (386, arm, ppc64le, s390x, riscv64, mips...). ioprioSupported
+// false tells lowerPriority to skip ioprio_set and keep the nice(2) call.
+const sysIoprioSet = 0
+
+const ioprioSupported = false
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package main
+
+// lowerPriority is a no-op stub for platforms without ioprio_set. It exists
+// so main.go can call --low-priority unconditionally regardless of GOOS.
+func lowerPriority() error {
+	return nil
+}
@@ -1,41 +1,29 @@
 package main
 
 import (
-	"bytes"
-	"crypto/sha256"
-	"encoding/hex"
+	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
-	"io"
+	"net/http"
 	"os"
 	"path/filepath"
-	"strconv"
+	"runtime"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/inhies/go-bytesize"
-	"golang.org/x/sys/unix"
-	"github.com/luckylittle/sizechecker/discord"
-	"github.com/luckylittle/sizechecker/pushover"
-
+	"github.com/luckylittle/sizechecker/notifier"
+	"github.com/luckylittle/sizechecker/scanner"
 )
 
-func getUsedSpace(path string) (int64, error) {
-	var size int64
-
-	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		if !info.IsDir() {
-			size += info.Size()
-		}
-		return nil
-	})
-
-	return size, err
-}
+// defaultMetricsWatchInterval is the --watch interval --metrics-addr implies
+// when --watch isn't also given. Serving /metrics for a single check-and-exit
+// pass isn't useful; an exporter needs to keep re-checking for the gauges to
+// mean anything.
+const defaultMetricsWatchInterval = 30 * time.Second
 
 func getAvailableSpace(dir string) (int64, error) {
 	var stat syscall.Statfs_t
@@ -45,82 +33,52 @@ func getAvailableSpace(dir string) (int64, error) {
 	return int64(stat.Bavail) * int64(stat.Bsize), nil
 }
 
-func getNotificationTimestampFilePath(webhookURL string) string {
-	hash := sha256.Sum256([]byte(webhookURL))
-	hashStr := hex.EncodeToString(hash[:])
-	return filepath.Join(os.TempDir(), "disk_space_checker_last_notification_"+hashStr)
+func cleanSizeString(size string) string {
+	return strings.ReplaceAll(size, " ", "")
 }
 
-func shouldSendNotification(webhookURL string, cooldown time.Duration) (bool, error) {
-	filePath := getNotificationTimestampFilePath(webhookURL)
-	//fmt.Println("Timestamp file path:", getNotificationTimestampFilePath(webhookURL))
-
-	file, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE, 0644)
-	if err != nil {
-		return false, fmt.Errorf("error opening timestamp file: %v", err)
-	}
-	defer file.Close()
-
-	if err := unix.Flock(int(file.Fd()), unix.LOCK_EX); err != nil {
-		return false, fmt.Errorf("error acquiring file lock: %v", err)
-	}
-	defer unix.Flock(int(file.Fd()), unix.LOCK_UN)
+// notifyTargets is a repeatable flag.Value collecting every --notify value,
+// e.g. --notify discord://... --notify slack://....
+type notifyTargets []string
 
-	data, err := io.ReadAll(file)
-	if err != nil {
-		return false, fmt.Errorf("error reading timestamp file: %v", err)
-	}
+func (n *notifyTargets) String() string { return strings.Join(*n, ",") }
 
-	lastSentStr := string(bytes.TrimSpace(data))
-	if lastSentStr == "" {
-		return true, nil
-	}
-
-	lastSentUnix, err := strconv.ParseInt(lastSentStr, 10, 64)
-	if err != nil {
-		return true, nil
-	}
-
-	lastSentTime := time.Unix(lastSentUnix, 0)
-	if time.Since(lastSentTime) >= cooldown {
-		return true, nil
-	}
-
-	return false, nil
+func (n *notifyTargets) Set(value string) error {
+	*n = append(*n, value)
+	return nil
 }
 
-func updateNotificationTimestamp(webhookURL string) error {
-	filePath := getNotificationTimestampFilePath(webhookURL)
+// excludeGlobs is a repeatable flag.Value collecting every --exclude glob.
+type excludeGlobs []string
 
-	file, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
-	if err != nil {
-		return fmt.Errorf("error opening timestamp file: %v", err)
-	}
-	defer file.Close()
-
-	if err := unix.Flock(int(file.Fd()), unix.LOCK_EX); err != nil {
-		return fmt.Errorf("error acquiring file lock: %v", err)
-	}
-	defer unix.Flock(int(file.Fd()), unix.LOCK_UN)
-
-	currentTime := strconv.FormatInt(time.Now().Unix(), 10)
-	if _, err := file.WriteString(currentTime); err != nil {
-		return fmt.Errorf("error writing timestamp file: %v", err)
-	}
+func (e *excludeGlobs) String() string { return strings.Join(*e, ",") }
 
+func (e *excludeGlobs) Set(value string) error {
+	*e = append(*e, value)
 	return nil
 }
 
-func cleanSizeString(size string) string {
-	return strings.ReplaceAll(size, " ", "")
-}
-
 func main() {
 	limitFlag := flag.String("limit", "", "Limit size (e.g., 50GB). For 'u' runtype, it's the maximum allowed used space; for 'a', it's the minimum required free space.")
-	runTypeFlag := flag.String("runtype", "", "'a' for available space check, 'u' for used space check")
-	discordFlag := flag.String("discord", "", "Discord webhook URL for notifications (optional)")
-	pushoverFlag := flag.String("o", "", "Trigger a Pushover notification. This requires `PUSHOVER_APITOKEN` and `PUSHOVER_USERKEY` to be set!")
+	runTypeFlag := flag.String("runtype", "", "'a' for available space check, 'u' for used space check, 'i' for inode exhaustion check (--limit takes an absolute inode count or a percentage, e.g. 95%)")
+	discordFlag := flag.String("discord", "", "Discord webhook URL for notifications (optional, shorthand for --notify discord://...)")
+	pushoverFlag := flag.String("o", "", "Trigger a Pushover notification (shorthand for --notify pushover://...). This requires `PUSHOVER_APITOKEN` and `PUSHOVER_USERKEY` to be set!")
 	cooldownFlag := flag.Duration("cooldown", time.Minute, "Cooldown duration between notifications (e.g., 1m, 30s)")
+	metricsAddrFlag := flag.String("metrics-addr", "", "Serve Prometheus metrics on this address (e.g., :9111). Implies --watch=30s unless --watch is already set.")
+	watchFlag := flag.Duration("watch", 0, "Keep running, re-checking on this interval (e.g., 30s, 5m) instead of exiting after one pass.")
+	scanConcurrencyFlag := flag.Int("scan-concurrency", runtime.NumCPU(), "Number of workers scanning directories concurrently.")
+	oneFilesystemFlag := flag.Bool("one-filesystem", false, "Don't descend into directories on a different filesystem than the scanned root (e.g. bind mounts).")
+	lowPriorityFlag := flag.Bool("low-priority", false, "Scan at idle I/O priority and nice 19 (Linux only; no-op elsewhere) so a large scan doesn't starve foreground workloads.")
+	unitsFlag := flag.String("units", unitsIEC, "Byte formatting for text output: 'iec' (GiB/MiB), 'si' (GB/MB), or 'raw' (plain bytes).")
+	formatFlag := flag.String("format", formatText, "Output format: 'text' or 'json'.")
+	daemonFlag := flag.Bool("daemon", false, "Keep running, notifying only on OK<->breach transitions, and serve /healthz and /readyz alongside --metrics-addr.")
+	intervalFlag := flag.Duration("interval", 5*time.Minute, "Re-check interval in --daemon mode (e.g. 30s, 5m).")
+	warnFlag := flag.String("warn", "", "Hysteresis warn threshold as a percentage of --limit (e.g. 80%), used with --daemon. Hysteresis only applies when --crit is also set; otherwise the raw breach/recovery signal is used.")
+	critFlag := flag.String("crit", "", "Hysteresis critical threshold as a percentage of --limit (e.g. 95%), used with --daemon. Hysteresis only applies when --warn is also set; otherwise the raw breach/recovery signal is used.")
+	var notifyFlag notifyTargets
+	flag.Var(&notifyFlag, "notify", "Notification target (repeatable), e.g. --notify discord://... --notify slack://... --notify smtp://user:pw@host/?to=ops@example.com")
+	var excludeFlag excludeGlobs
+	flag.Var(&excludeFlag, "exclude", "Glob pattern to skip while scanning (repeatable), matched against the entry's name or full path.")
 	flag.Parse()
 
 	if *limitFlag == "" {
@@ -129,8 +87,20 @@ func main() {
 		os.Exit(2)
 	}
 
-	if *runTypeFlag != "u" && *runTypeFlag != "a" {
-		fmt.Println("Error: --runtype flag must be 'u' for used space or 'a' for available space.")
+	if *runTypeFlag != "u" && *runTypeFlag != "a" && *runTypeFlag != "i" {
+		fmt.Println("Error: --runtype flag must be 'u' for used space, 'a' for available space, or 'i' for inode exhaustion.")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *unitsFlag != unitsIEC && *unitsFlag != unitsSI && *unitsFlag != unitsRaw {
+		fmt.Println("Error: --units flag must be 'iec', 'si', or 'raw'.")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *formatFlag != formatText && *formatFlag != formatJSON {
+		fmt.Println("Error: --format flag must be 'text' or 'json'.")
 		flag.Usage()
 		os.Exit(2)
 	}
@@ -158,89 +128,275 @@ func main() {
 		os.Exit(2)
 	}
 
-	limitBytes, err := bytesize.Parse(cleanSizeString(*limitFlag))
+	var limitBytes bytesize.ByteSize
+	if *runTypeFlag != "i" {
+		limitBytes, err = bytesize.Parse(cleanSizeString(*limitFlag))
+		if err != nil {
+			fmt.Printf("Error parsing limit size: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
+	targetURNs := append([]string{}, notifyFlag...)
+	if *discordFlag != "" {
+		targetURNs = append(targetURNs, "discord://"+strings.TrimPrefix(*discordFlag, "https://discord.com/api/webhooks/"))
+	}
+	if *pushoverFlag != "" {
+		targetURNs = append(targetURNs, "pushover://")
+	}
+
+	targets, err := notifier.ParseAll(targetURNs)
 	if err != nil {
-		fmt.Printf("Error parsing limit size: %v\n", err)
+		fmt.Printf("Error: %v\n", err)
 		os.Exit(2)
 	}
+	limiter := notifier.NewRateLimiter(*cooldownFlag)
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	scanOpts := scanner.Options{
+		Concurrency:   *scanConcurrencyFlag,
+		Excludes:      excludeFlag,
+		OneFilesystem: *oneFilesystemFlag,
+	}
+
+	if *lowPriorityFlag {
+		if err := lowerPriority(); err != nil {
+			fmt.Printf("Error lowering scan priority: %v\n", err)
+		}
+	}
+
+	if *daemonFlag {
+		warn, err := parsePercent(*warnFlag)
+		if err != nil {
+			fmt.Printf("Error parsing --warn: %v\n", err)
+			os.Exit(2)
+		}
+		crit, err := parsePercent(*critFlag)
+		if err != nil {
+			fmt.Printf("Error parsing --crit: %v\n", err)
+			os.Exit(2)
+		}
+
+		metricsAddr := *metricsAddrFlag
+		if metricsAddr == "" {
+			metricsAddr = ":9111"
+		}
+		state := newDaemonState()
+		startMetricsServer(metricsAddr, func(mux *http.ServeMux) {
+			state.registerHealthHandlers(mux, *intervalFlag)
+		})
+
+		runDaemon(absDir, *runTypeFlag, limitBytes, *limitFlag, hostname, *unitsFlag, *formatFlag, warn, crit, *intervalFlag, targets, limiter, scanOpts, state)
+		return
+	}
+
+	metricsEnabled := *metricsAddrFlag != ""
+	if metricsEnabled {
+		startMetricsServer(*metricsAddrFlag)
+		if *watchFlag <= 0 {
+			*watchFlag = defaultMetricsWatchInterval
+		}
+	}
 
+	if *watchFlag <= 0 {
+		exitCode, _ := runCheck(absDir, *runTypeFlag, limitBytes, *limitFlag, hostname, *unitsFlag, *formatFlag, targets, limiter, scanOpts, metricsEnabled)
+		os.Exit(exitCode)
+	}
+
+	ticker := time.NewTicker(*watchFlag)
+	defer ticker.Stop()
+
+	runCheck(absDir, *runTypeFlag, limitBytes, *limitFlag, hostname, *unitsFlag, *formatFlag, targets, limiter, scanOpts, metricsEnabled)
+	for range ticker.C {
+		runCheck(absDir, *runTypeFlag, limitBytes, *limitFlag, hostname, *unitsFlag, *formatFlag, targets, limiter, scanOpts, metricsEnabled)
+	}
+}
+
+// runCheck performs a single check-and-notify pass and returns the process
+// exit code that pass would warrant in single-shot mode (0 = within limits,
+// 1 = breached, 2 = error), alongside the notifier.Message it computed (the
+// zero Message on a 2). In --watch mode the caller ignores both; --daemon
+// mode uses the Message to apply its hysteresis bands without re-scanning.
+// rawLimit is the unparsed --limit value, used by the 'i' runtype which
+// accepts an absolute count or a percentage. units and format control how
+// the result is reported on stdout (--units, --format); notifier backends
+// always receive the full structured Message regardless. metricsEnabled
+// gates the extra scanner.Walk the 'a' runtype would otherwise need purely
+// to populate sizechecker_used_bytes; a plain one-shot/cron 'a' check never
+// needed a directory scan before metrics existed, and shouldn't pay for one
+// now unless something is actually scraping the gauge.
+func runCheck(absDir, runType string, limitBytes bytesize.ByteSize, rawLimit, hostname, units, format string, targets []notifier.Target, limiter *notifier.RateLimiter, scanOpts scanner.Options, metricsEnabled bool) (int, notifier.Message) {
 	var (
-		multiByteSize bytesize.ByteSize
-		message       string
+		message                         string
+		breached                        bool
+		usedVal, availableVal, limitVal int64
 	)
 
-	switch *runTypeFlag {
+	inodesTotal, inodesFree, inodeErr := getInodeStats(absDir)
+	if inodeErr == nil {
+		updateInodeMetrics(absDir, runType, inodesTotal-inodesFree, inodesFree)
+	}
+
+	switch runType {
 	case "u":
-		usedBytes, err := getUsedSpace(absDir)
+		usedBytes, err := scanner.Walk(absDir, scanOpts)
 		if err != nil {
 			fmt.Printf("Error getting used space: %v\n", err)
-			os.Exit(2)
+			return 2, notifier.Message{}
 		}
-		multiByteSize = bytesize.ByteSize(usedBytes)
-		if multiByteSize >= limitBytes {
-			message = fmt.Sprintf("Warning: %s used in %s, which is beyond the limit of %s.",
-				multiByteSize, absDir, limitBytes)
-			fmt.Println(message)
+		availableBytes, err := getAvailableSpace(absDir)
+		if err != nil {
+			fmt.Printf("Error getting available space: %v\n", err)
+			return 2, notifier.Message{}
+		}
+		updateVolumeMetrics(absDir, runType, usedBytes, availableBytes, int64(limitBytes))
+		usedVal, availableVal, limitVal = usedBytes, availableBytes, int64(limitBytes)
+
+		if bytesize.ByteSize(usedBytes) >= limitBytes {
+			breached = true
+			message = fmt.Sprintf("Warning: %s used in %s, which is beyond the limit of %s. (inodes: %d used, %d free)",
+				formatBytes(usedBytes, units), absDir, formatBytes(int64(limitBytes), units), inodesTotal-inodesFree, inodesFree)
 		} else {
-			fmt.Printf("Used space is within acceptable limits: %s used of %s.\n", multiByteSize, limitBytes)
-			os.Exit(0)
+			message = fmt.Sprintf("Used space is within acceptable limits: %s used of %s.",
+				formatBytes(usedBytes, units), formatBytes(int64(limitBytes), units))
 		}
 	case "a":
 		availableBytes, err := getAvailableSpace(absDir)
 		if err != nil {
 			fmt.Printf("Error getting available space: %v\n", err)
-			os.Exit(2)
+			return 2, notifier.Message{}
+		}
+
+		var usedBytes int64
+		if metricsEnabled {
+			usedBytes, err = scanner.Walk(absDir, scanOpts)
+			if err != nil {
+				fmt.Printf("Error getting used space: %v\n", err)
+				return 2, notifier.Message{}
+			}
+		}
+		updateVolumeMetrics(absDir, runType, usedBytes, availableBytes, int64(limitBytes))
+		usedVal, availableVal, limitVal = usedBytes, availableBytes, int64(limitBytes)
+
+		if bytesize.ByteSize(availableBytes) < limitBytes {
+			breached = true
+			message = fmt.Sprintf("Warning: Only %s available in %s, which is below the limit of %s. (inodes: %d used, %d free)",
+				formatBytes(availableBytes, units), absDir, formatBytes(int64(limitBytes), units), inodesTotal-inodesFree, inodesFree)
+		} else {
+			message = fmt.Sprintf("Sufficient space: %s available.", formatBytes(availableBytes, units))
+		}
+	case "i":
+		if inodeErr != nil {
+			fmt.Printf("Error getting inode stats: %v\n", inodeErr)
+			return 2, notifier.Message{}
+		}
+		inodeLimit, err := parseInodeLimit(rawLimit, inodesTotal)
+		if err != nil {
+			fmt.Printf("Error parsing inode limit: %v\n", err)
+			return 2, notifier.Message{}
 		}
-		multiByteSize = bytesize.ByteSize(availableBytes)
-		if multiByteSize < limitBytes {
-			message = fmt.Sprintf("Warning: Only %s available in %s, which is below the limit of %s.",
-				multiByteSize, absDir, limitBytes)
-			fmt.Println(message)
+
+		usedInodes := inodesTotal - inodesFree
+		usedVal, availableVal, limitVal = int64(usedInodes), int64(inodesFree), int64(inodeLimit)
+
+		if usedInodes >= inodeLimit {
+			breached = true
+			message = fmt.Sprintf("Warning: %d inodes used in %s (of %d total, %d free), which is beyond the limit of %d.",
+				usedInodes, absDir, inodesTotal, inodesFree, inodeLimit)
 		} else {
-			fmt.Printf("Sufficient space: %s available.\n", multiByteSize)
-			os.Exit(0)
+			message = fmt.Sprintf("Inode usage is within acceptable limits: %d used of %d total (%d free).", usedInodes, inodesTotal, inodesFree)
 		}
 	default:
-		fmt.Println("Error: Invalid --runtype value. Use 'u' for used space or 'a' for available space.")
+		fmt.Println("Error: Invalid --runtype value. Use 'u' for used space, 'a' for available space, or 'i' for inode exhaustion.")
 		flag.Usage()
-		os.Exit(2)
+		return 2, notifier.Message{}
 	}
 
-	if *discordFlag != "" {
-		sendNotification, err := shouldSendNotification(*discordFlag, *cooldownFlag)
-		if err != nil {
-			fmt.Printf("Error checking notification cooldown: %v\n", err)
-		} else if sendNotification {
-			if err := sendDiscordNotification(*discordFlag, message); err != nil {
-				fmt.Printf("Error sending Discord notification: %v\n", err)
-			} else {
-				fmt.Println("Discord notification sent successfully.")
-				if err := updateNotificationTimestamp(*discordFlag); err != nil {
-					fmt.Printf("Error updating notification timestamp: %v\n", err)
-				}
-			}
-		} else {
-			fmt.Println("Notification not sent due to rate limiting.")
-		}
+	var inodesUsedVal, inodesFreeVal int64
+	if inodeErr == nil {
+		inodesUsedVal, inodesFreeVal = int64(inodesTotal-inodesFree), int64(inodesFree)
 	}
 
-	if *pushoverFlag != "" {
-		sendNotification, err := shouldSendNotification(*pushoverFlag, *cooldownFlag)
+	severity := "ok"
+	if breached {
+		severity = "warning"
+	}
+	msg := notifier.Message{
+		Directory:      absDir,
+		RunType:        runType,
+		Hostname:       hostname,
+		Severity:       severity,
+		UsedBytes:      usedVal,
+		AvailableBytes: availableVal,
+		LimitBytes:     limitVal,
+		InodesUsed:     inodesUsedVal,
+		InodesFree:     inodesFreeVal,
+		Breached:       breached,
+		Text:           message,
+	}
+
+	if format == formatJSON {
+		payload, err := json.Marshal(msg)
 		if err != nil {
-			fmt.Printf("Error checking notification cooldown: %v\n", err)
-		} else if sendNotification {
-			if err := pushoverNotification(*pushoverFlag, message); err != nil {
-				fmt.Printf("Error sending Pusover notification: %v\n", err)
-			} else {
-				fmt.Println("Pushover notification sent successfully.")
-				if err := updateNotificationTimestamp(*pushoverFlag); err != nil {
-					fmt.Printf("Error updating notification timestamp: %v\n", err)
-				}
-			}
-		} else {
-			fmt.Println("Notification not sent due to rate limiting.")
+			fmt.Printf("Error marshaling JSON output: %v\n", err)
+			return 2, notifier.Message{}
 		}
+		fmt.Println(string(payload))
+	} else {
+		fmt.Println(message)
+	}
+
+	if !breached {
+		return 0, msg
 	}
 
-	os.Exit(1)
+	sendNotifications(targets, limiter, msg)
+
+	return 1, msg
+}
+
+// notifySendTimeout bounds a single target's Send call. Backends already
+// set their own HTTP client timeouts, but this is a second, caller-side
+// backstop so sendNotifications' wg.Wait() can't hang on a target that
+// doesn't respect its own timeout.
+const notifySendTimeout = 15 * time.Second
+
+// sendNotifications fans msg out to every target in parallel, each gated by
+// its own cooldown so one slow or rate-limited backend never blocks another.
+func sendNotifications(targets []notifier.Target, limiter *notifier.RateLimiter, msg notifier.Message) {
+	var wg sync.WaitGroup
+	for _, target := range targets {
+		wg.Add(1)
+		go func(target notifier.Target) {
+			defer wg.Done()
+
+			allowed, err := limiter.Allow(target.Identity)
+			if err != nil {
+				fmt.Printf("Error checking notification cooldown for %s: %v\n", target.Notifier.Name(), err)
+				return
+			}
+			if !allowed {
+				fmt.Printf("Notification not sent via %s due to rate limiting.\n", target.Notifier.Name())
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), notifySendTimeout)
+			defer cancel()
+			if err := target.Notifier.Send(ctx, msg); err != nil {
+				fmt.Printf("Error sending %s notification: %v\n", target.Notifier.Name(), err)
+				return
+			}
+
+			fmt.Printf("%s notification sent successfully.\n", target.Notifier.Name())
+			notificationsSentTotal.WithLabelValues(target.Notifier.Name(), msg.Directory).Inc()
+			if err := limiter.MarkSent(target.Identity); err != nil {
+				fmt.Printf("Error updating notification timestamp for %s: %v\n", target.Notifier.Name(), err)
+			}
+		}(target)
+	}
+	wg.Wait()
 }
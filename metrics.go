@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// volumeMetricsVecs mirrors the labeled-gauge-per-directory pattern used by
+// keepstore-style services: every monitored directory/runtype pair gets its
+// own series instead of one process-wide gauge.
+var volumeMetricsVecs = struct {
+	used        *prometheus.GaugeVec
+	available   *prometheus.GaugeVec
+	limit       *prometheus.GaugeVec
+	inodesUsed  *prometheus.GaugeVec
+	inodesFree  *prometheus.GaugeVec
+}{
+	used: promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "sizechecker_used_bytes",
+		Help: "Bytes currently used under the monitored directory.",
+	}, []string{"directory", "runtype"}),
+	available: promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "sizechecker_available_bytes",
+		Help: "Bytes currently available on the filesystem backing the monitored directory.",
+	}, []string{"directory", "runtype"}),
+	limit: promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "sizechecker_limit_bytes",
+		Help: "Configured --limit for the monitored directory, in bytes.",
+	}, []string{"directory", "runtype"}),
+	inodesUsed: promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "sizechecker_inodes_used",
+		Help: "Inodes currently used on the filesystem backing the monitored directory.",
+	}, []string{"directory", "runtype"}),
+	inodesFree: promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "sizechecker_inodes_free",
+		Help: "Inodes currently free on the filesystem backing the monitored directory.",
+	}, []string{"directory", "runtype"}),
+}
+
+var notificationsSentTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "sizechecker_notifications_sent_total",
+	Help: "Notifications sent, labeled by backend and directory.",
+}, []string{"backend", "directory"})
+
+// startMetricsServer starts the Prometheus HTTP handler on addr in the
+// background, along with any extra handlers registered by the caller (used
+// by --daemon to add /healthz and /readyz to the same listener). It never
+// blocks the caller; a failure to bind is fatal since it means the exporter
+// can't do the one thing --metrics-addr asked for.
+func startMetricsServer(addr string, register ...func(*http.ServeMux)) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	for _, r := range register {
+		r(mux)
+	}
+
+	go func() {
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			fmt.Printf("Error: metrics server on %s stopped: %v\n", addr, err)
+			panic(err)
+		}
+	}()
+}
+
+// updateVolumeMetrics refreshes the gauge series for a single monitored
+// directory. Called once per --watch tick (or once for a single-shot run).
+func updateVolumeMetrics(dir, runtype string, usedBytes, availableBytes, limitBytes int64) {
+	volumeMetricsVecs.used.WithLabelValues(dir, runtype).Set(float64(usedBytes))
+	volumeMetricsVecs.available.WithLabelValues(dir, runtype).Set(float64(availableBytes))
+	volumeMetricsVecs.limit.WithLabelValues(dir, runtype).Set(float64(limitBytes))
+}
+
+// updateInodeMetrics refreshes the inode gauge series for a single
+// monitored directory, alongside the byte-based series above.
+func updateInodeMetrics(dir, runtype string, inodesUsed, inodesFree uint64) {
+	volumeMetricsVecs.inodesUsed.WithLabelValues(dir, runtype).Set(float64(inodesUsed))
+	volumeMetricsVecs.inodesFree.WithLabelValues(dir, runtype).Set(float64(inodesFree))
+}
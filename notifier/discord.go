@@ -0,0 +1,52 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// discordNotifier posts to a Discord incoming webhook. The --notify value
+// "discord://<webhook-id>/<webhook-token>" is reassembled into the standard
+// https://discord.com/api/webhooks/<id>/<token> URL.
+type discordNotifier struct {
+	webhookURL string
+}
+
+func newDiscordFromURL(u *url.URL) (Notifier, error) {
+	id := u.Host
+	token := u.Path
+	if id == "" || token == "" || token == "/" {
+		return nil, fmt.Errorf("notifier: discord target must be discord://<webhook-id>/<token>")
+	}
+	return &discordNotifier{webhookURL: "https://discord.com/api/webhooks/" + id + token}, nil
+}
+
+func (d *discordNotifier) Name() string { return "discord" }
+
+func (d *discordNotifier) Send(ctx context.Context, msg Message) error {
+	payload, err := json.Marshal(map[string]string{"content": msg.Text})
+	if err != nil {
+		return fmt.Errorf("error marshaling Discord payload: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("error building Discord request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending Discord notification: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("discord webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
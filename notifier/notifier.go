@@ -0,0 +1,126 @@
+// Package notifier decouples sizechecker's alerting backends from main.go.
+// Each backend (Discord, Pushover, Slack, ...) implements Notifier, and
+// callers fan a single Message out to as many of them as --notify lists.
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// httpClientTimeout bounds every outbound HTTP request an http-backed
+// Notifier makes. Without it a slow or unresponsive sink can hang the
+// goroutine sending to it indefinitely, and sendNotifications' wg.Wait()
+// means that one bad target wedges every other target's notification too.
+const httpClientTimeout = 10 * time.Second
+
+// httpClient is shared by every http-backed Notifier so they all get the
+// same timeout without each backend having to remember to set one.
+var httpClient = &http.Client{Timeout: httpClientTimeout}
+
+// Message carries everything a backend needs to render an alert. It is the
+// same shape serialized for webhook-style sinks and the --format json
+// output mode, so `sizechecker ... --format json | jq` and a webhook sink
+// see identical payloads. UsedBytes/AvailableBytes/LimitBytes carry the
+// runtype's own check value (bytes for 'u'/'a', an inode count for 'i');
+// InodesUsed/InodesFree are always a byte count's inode counterpart,
+// populated regardless of runtype, so consumers get combined byte and
+// inode state without having to guess which unit a field is in.
+type Message struct {
+	Directory      string `json:"directory"`
+	RunType        string `json:"runtype"`
+	Hostname       string `json:"hostname"`
+	Severity       string `json:"severity"`
+	UsedBytes      int64  `json:"used"`
+	AvailableBytes int64  `json:"available"`
+	LimitBytes     int64  `json:"limit"`
+	InodesUsed     int64  `json:"inodes_used"`
+	InodesFree     int64  `json:"inodes_free"`
+	Breached       bool   `json:"breached"`
+	Text           string `json:"text"`
+}
+
+// Notifier is a single alerting backend. Implementations must be safe to
+// call from multiple goroutines, since Fan-out sends to every target
+// concurrently.
+type Notifier interface {
+	// Name identifies the backend for logging and the
+	// sizechecker_notifications_sent_total counter, e.g. "discord".
+	Name() string
+	// Send delivers msg, or returns an error describing why it couldn't.
+	Send(ctx context.Context, msg Message) error
+}
+
+// Target pairs a parsed Notifier with the raw identity string (the full
+// --notify value) it was built from, which RateLimiter uses as its key.
+type Target struct {
+	Notifier Notifier
+	Identity string
+}
+
+// credentialPattern matches the userinfo component of a URL (everything
+// between "://" and the next "@"), which is where --notify targets embed
+// secrets like an SMTP password or a Telegram bot token.
+var credentialPattern = regexp.MustCompile(`://[^/@]*@`)
+
+// redactTarget masks any userinfo in a --notify value so it's safe to fold
+// into an error message main.go prints straight to stdout: a typo'd scheme
+// or malformed URL shouldn't leak the credential the rest of it carries.
+func redactTarget(raw string) string {
+	return credentialPattern.ReplaceAllString(raw, "://[redacted]@")
+}
+
+// Parse turns one --notify value (e.g. "discord://<webhook-id>/<token>" or
+// "smtp://user:pw@host:587/?to=ops@example.com") into a Target. The scheme
+// selects the backend; everything else is backend-specific.
+func Parse(raw string) (Target, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return Target{}, fmt.Errorf("notifier: invalid target %q: %s", redactTarget(raw), redactTarget(err.Error()))
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	var n Notifier
+	switch scheme {
+	case "discord":
+		n, err = newDiscordFromURL(u)
+	case "pushover":
+		n, err = newPushoverFromURL(u)
+	case "slack":
+		n, err = newSlackFromURL(u)
+	case "telegram":
+		n, err = newTelegramFromURL(u)
+	case "smtp", "smtps":
+		n, err = newSMTPFromURL(u)
+	case "ntfy":
+		n, err = newNtfyFromURL(u)
+	case "webhook", "webhook+https", "webhook+http":
+		n, err = newWebhookFromURL(u)
+	default:
+		return Target{}, fmt.Errorf("notifier: unknown scheme %q in target %q", u.Scheme, redactTarget(raw))
+	}
+	if err != nil {
+		return Target{}, err
+	}
+
+	return Target{Notifier: n, Identity: raw}, nil
+}
+
+// ParseAll parses every raw target, stopping at the first error so a typo
+// in one --notify value is caught before any alert is sent.
+func ParseAll(raws []string) ([]Target, error) {
+	targets := make([]Target, 0, len(raws))
+	for _, raw := range raws {
+		t, err := Parse(raw)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, t)
+	}
+	return targets, nil
+}
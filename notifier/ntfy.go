@@ -0,0 +1,44 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ntfyNotifier publishes to an ntfy.sh (or self-hosted) topic.
+// "ntfy://ntfy.sh/my-topic" publishes a plain-text body to that topic URL.
+type ntfyNotifier struct {
+	topicURL string
+}
+
+func newNtfyFromURL(u *url.URL) (Notifier, error) {
+	if u.Host == "" || u.Path == "" || u.Path == "/" {
+		return nil, fmt.Errorf("notifier: ntfy target must be ntfy://<server>/<topic>")
+	}
+	return &ntfyNotifier{topicURL: "https://" + u.Host + u.Path}, nil
+}
+
+func (n *ntfyNotifier) Name() string { return "ntfy" }
+
+func (n *ntfyNotifier) Send(ctx context.Context, msg Message) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.topicURL, strings.NewReader(msg.Text))
+	if err != nil {
+		return fmt.Errorf("error building ntfy request: %v", err)
+	}
+	req.Header.Set("Title", "sizechecker: "+msg.Directory)
+	req.Header.Set("Priority", "default")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending ntfy notification: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("ntfy server returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// pushoverNotifier sends via the Pushover API. Credentials stay in
+// PUSHOVER_APITOKEN/PUSHOVER_USERKEY as before; the --notify target only
+// carries an optional priority, e.g. "pushover://?priority=1".
+type pushoverNotifier struct {
+	apiToken string
+	userKey  string
+	priority string
+}
+
+func newPushoverFromURL(u *url.URL) (Notifier, error) {
+	apiToken := os.Getenv("PUSHOVER_APITOKEN")
+	userKey := os.Getenv("PUSHOVER_USERKEY")
+	if apiToken == "" || userKey == "" {
+		return nil, fmt.Errorf("notifier: pushover requires PUSHOVER_APITOKEN and PUSHOVER_USERKEY to be set")
+	}
+	return &pushoverNotifier{
+		apiToken: apiToken,
+		userKey:  userKey,
+		priority: u.Query().Get("priority"),
+	}, nil
+}
+
+func (p *pushoverNotifier) Name() string { return "pushover" }
+
+func (p *pushoverNotifier) Send(ctx context.Context, msg Message) error {
+	form := url.Values{
+		"token":   {p.apiToken},
+		"user":    {p.userKey},
+		"message": {msg.Text},
+		"title":   {"sizechecker: " + msg.Directory},
+	}
+	if p.priority != "" {
+		form.Set("priority", p.priority)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.pushover.net/1/messages.json", strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("error building Pushover request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending Pushover notification: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("pushover API returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package notifier
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// RateLimiter is the cooldown mechanism sizechecker used to gate behind
+// package-level functions keyed on the raw webhook URL. It now keys on a
+// notifier's identity (its --notify value) so any backend, not just the
+// original Discord/Pushover pair, gets the same one-file-per-target cooldown.
+type RateLimiter struct {
+	cooldown time.Duration
+}
+
+// NewRateLimiter returns a RateLimiter that allows one notification per
+// identity every cooldown.
+func NewRateLimiter(cooldown time.Duration) *RateLimiter {
+	return &RateLimiter{cooldown: cooldown}
+}
+
+func timestampFilePath(identity string) string {
+	hash := sha256.Sum256([]byte(identity))
+	hashStr := hex.EncodeToString(hash[:])
+	return filepath.Join(os.TempDir(), "disk_space_checker_last_notification_"+hashStr)
+}
+
+// Allow reports whether a notification for identity may be sent right now.
+func (r *RateLimiter) Allow(identity string) (bool, error) {
+	filePath := timestampFilePath(identity)
+
+	file, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return false, fmt.Errorf("error opening timestamp file: %v", err)
+	}
+	defer file.Close()
+
+	if err := unix.Flock(int(file.Fd()), unix.LOCK_EX); err != nil {
+		return false, fmt.Errorf("error acquiring file lock: %v", err)
+	}
+	defer unix.Flock(int(file.Fd()), unix.LOCK_UN)
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return false, fmt.Errorf("error reading timestamp file: %v", err)
+	}
+
+	lastSentStr := string(bytes.TrimSpace(data))
+	if lastSentStr == "" {
+		return true, nil
+	}
+
+	lastSentUnix, err := strconv.ParseInt(lastSentStr, 10, 64)
+	if err != nil {
+		return true, nil
+	}
+
+	lastSentTime := time.Unix(lastSentUnix, 0)
+	return time.Since(lastSentTime) >= r.cooldown, nil
+}
+
+// MarkSent records that a notification for identity was just sent.
+func (r *RateLimiter) MarkSent(identity string) error {
+	filePath := timestampFilePath(identity)
+
+	file, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("error opening timestamp file: %v", err)
+	}
+	defer file.Close()
+
+	if err := unix.Flock(int(file.Fd()), unix.LOCK_EX); err != nil {
+		return fmt.Errorf("error acquiring file lock: %v", err)
+	}
+	defer unix.Flock(int(file.Fd()), unix.LOCK_UN)
+
+	currentTime := strconv.FormatInt(time.Now().Unix(), 10)
+	if _, err := file.WriteString(currentTime); err != nil {
+		return fmt.Errorf("error writing timestamp file: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// slackNotifier posts to a Slack Incoming Webhook. "slack://hooks.slack.com/services/T0/B0/XXX"
+// is reassembled into the https URL Slack hands out.
+type slackNotifier struct {
+	webhookURL string
+}
+
+func newSlackFromURL(u *url.URL) (Notifier, error) {
+	if u.Host == "" || u.Path == "" {
+		return nil, fmt.Errorf("notifier: slack target must be slack://hooks.slack.com/services/<id>/<id>/<token>")
+	}
+	return &slackNotifier{webhookURL: "https://" + u.Host + u.Path}, nil
+}
+
+func (s *slackNotifier) Name() string { return "slack" }
+
+func (s *slackNotifier) Send(ctx context.Context, msg Message) error {
+	payload, err := json.Marshal(map[string]string{"text": msg.Text})
+	if err != nil {
+		return fmt.Errorf("error marshaling Slack payload: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("error building Slack request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending Slack notification: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("slack webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"net/url"
+	"strings"
+)
+
+// smtpNotifier sends a plain-text email via net/smtp. The target is
+// "smtp://user:pw@host:port/?to=ops@example.com,oncall@example.com" with
+// credentials and recipients both carried in the URL.
+type smtpNotifier struct {
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+}
+
+func newSMTPFromURL(u *url.URL) (Notifier, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("notifier: smtp target must be smtp://[user:pw@]host:port/?to=a@b.com")
+	}
+	to := strings.Split(u.Query().Get("to"), ",")
+	if len(to) == 0 || to[0] == "" {
+		return nil, fmt.Errorf("notifier: smtp target is missing ?to=")
+	}
+
+	from := u.Query().Get("from")
+	if from == "" {
+		from = "sizechecker@localhost"
+	}
+
+	var auth smtp.Auth
+	host := u.Hostname()
+	if u.User != nil {
+		password, _ := u.User.Password()
+		auth = smtp.PlainAuth("", u.User.Username(), password, host)
+	}
+
+	return &smtpNotifier{addr: u.Host, auth: auth, from: from, to: to}, nil
+}
+
+func (s *smtpNotifier) Name() string { return "smtp" }
+
+func (s *smtpNotifier) Send(ctx context.Context, msg Message) error {
+	subject := fmt.Sprintf("sizechecker: %s on %s", msg.Severity, msg.Directory)
+	body := fmt.Sprintf("To: %s\r\nSubject: %s\r\n\r\n%s\r\n",
+		strings.Join(s.to, ", "), subject, msg.Text)
+
+	// net/smtp has no context-aware API, so a hung connection is bounded by
+	// racing it against ctx instead; the SendMail goroutine is leaked if it
+	// never returns, but the caller isn't blocked waiting on it.
+	done := make(chan error, 1)
+	go func() { done <- smtp.SendMail(s.addr, s.auth, s.from, s.to, []byte(body)) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("error sending SMTP notification: %v", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("error sending SMTP notification: %v", ctx.Err())
+	}
+}
@@ -0,0 +1,59 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// telegramNotifier sends through the Telegram Bot API.
+// "telegram://<bot-token>@telegram?chat_id=<chat-id>" carries the bot token
+// as the userinfo component, matching how other tools URL-encode secrets.
+type telegramNotifier struct {
+	botToken string
+	chatID   string
+}
+
+func newTelegramFromURL(u *url.URL) (Notifier, error) {
+	if u.User == nil || u.User.Username() == "" {
+		return nil, fmt.Errorf("notifier: telegram target must be telegram://<bot-token>@telegram?chat_id=<id>")
+	}
+	chatID := u.Query().Get("chat_id")
+	if chatID == "" {
+		return nil, fmt.Errorf("notifier: telegram target is missing chat_id")
+	}
+	botToken := u.User.Username()
+	if secret, ok := u.User.Password(); ok {
+		botToken += ":" + secret
+	}
+	return &telegramNotifier{botToken: botToken, chatID: chatID}, nil
+}
+
+func (t *telegramNotifier) Name() string { return "telegram" }
+
+func (t *telegramNotifier) Send(ctx context.Context, msg Message) error {
+	apiURL := "https://api.telegram.org/bot" + t.botToken + "/sendMessage"
+	form := url.Values{
+		"chat_id": {t.chatID},
+		"text":    {msg.Text},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("error building Telegram request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending Telegram notification: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("telegram API returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// webhookNotifier is the generic fallback: it POSTs the Message as JSON to
+// an arbitrary URL. "webhook://example.com/hook" and "webhook+http://..."
+// both resolve to a concrete http(s) URL; "webhook+http" opts out of TLS.
+type webhookNotifier struct {
+	url string
+}
+
+func newWebhookFromURL(u *url.URL) (Notifier, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("notifier: webhook target must be webhook://<host>/<path>")
+	}
+	scheme := "https"
+	if strings.HasSuffix(u.Scheme, "+http") {
+		scheme = "http"
+	}
+	target := scheme + "://" + u.Host + u.Path
+	if u.RawQuery != "" {
+		target += "?" + u.RawQuery
+	}
+	return &webhookNotifier{url: target}, nil
+}
+
+func (w *webhookNotifier) Name() string { return "webhook" }
+
+func (w *webhookNotifier) Send(ctx context.Context, msg Message) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("error marshaling webhook payload: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("error building webhook request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending webhook notification: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+// Package scanner implements the concurrent directory walker behind
+// sizechecker's used-space check. It replaces a single-threaded
+// filepath.Walk, which can't keep up on trees with millions of files spread
+// across many mounts.
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+// Options configures a Walk. The zero value scans everything with one
+// worker per CPU.
+type Options struct {
+	// Concurrency is the number of workers pulling directories off the
+	// work channel. Defaults to runtime.NumCPU().
+	Concurrency int
+	// Excludes are glob patterns matched against each entry's basename and
+	// its full path from root; matching directories are not descended into
+	// and matching files are not counted.
+	Excludes []string
+	// OneFilesystem stops the walker from descending into directories
+	// that live on a different device than the root (e.g. bind mounts).
+	OneFilesystem bool
+}
+
+// walker sums file sizes under a root directory using a fixed pool of
+// workers pulling paths off a channel. Discovered subdirectories are pushed
+// back onto the same channel; a WaitGroup counter tracks outstanding work so
+// the channel can be closed once every directory has been scanned.
+type walker struct {
+	opts    Options
+	size    atomic.Int64
+	wg      sync.WaitGroup
+	paths   chan string
+	rootDev uint64
+
+	errMu sync.Mutex
+	errs  []error
+}
+
+// Walk sums the size of every regular file under root and returns the
+// total, descending concurrently according to opts. The returned error
+// aggregates every directory that couldn't be read or file that couldn't be
+// stat'd; the returned total is still the sum of everything that *was*
+// readable, so callers can decide whether a partial count is good enough.
+func Walk(root string, opts Options) (int64, error) {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = runtime.NumCPU()
+	}
+	w := &walker{opts: opts, paths: make(chan string, 4096)}
+
+	if opts.OneFilesystem {
+		var stat syscall.Stat_t
+		if err := syscall.Stat(root, &stat); err != nil {
+			return 0, err
+		}
+		w.rootDev = uint64(stat.Dev)
+	}
+
+	for i := 0; i < opts.Concurrency; i++ {
+		go w.work()
+	}
+
+	w.wg.Add(1)
+	w.paths <- root
+
+	w.wg.Wait()
+	close(w.paths)
+
+	w.errMu.Lock()
+	err := errors.Join(w.errs...)
+	w.errMu.Unlock()
+
+	return w.size.Load(), err
+}
+
+// addErr records a per-entry failure without aborting the rest of the walk;
+// a directory it can't read shouldn't stop it from counting the ones it can.
+func (w *walker) addErr(err error) {
+	w.errMu.Lock()
+	w.errs = append(w.errs, err)
+	w.errMu.Unlock()
+}
+
+func (w *walker) work() {
+	for dir := range w.paths {
+		w.scanDir(dir)
+	}
+}
+
+func (w *walker) scanDir(dir string) {
+	defer w.wg.Done()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		w.addErr(fmt.Errorf("scanner: reading %s: %w", dir, err))
+		return
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if w.isExcluded(path, entry.Name()) {
+			continue
+		}
+
+		if entry.IsDir() {
+			if w.opts.OneFilesystem && !w.sameFilesystem(path) {
+				continue
+			}
+			w.wg.Add(1)
+			go func(p string) { w.paths <- p }(path)
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			w.addErr(fmt.Errorf("scanner: stat %s: %w", path, err))
+			continue
+		}
+		if info.Mode().IsRegular() {
+			w.size.Add(info.Size())
+		}
+	}
+}
+
+// isExcluded matches an --exclude pattern against both the entry's basename
+// and its full path from root. filepath.Match requires a whole-string match
+// and its '*' never crosses '/', so a pattern like "*.tmp" would never match
+// anything if only matched against the full path; matching the basename too
+// is what makes patterns like that behave the way users expect.
+func (w *walker) isExcluded(path, name string) bool {
+	for _, pattern := range w.opts.Excludes {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, path); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *walker) sameFilesystem(path string) bool {
+	var stat syscall.Stat_t
+	if err := syscall.Stat(path, &stat); err != nil {
+		return false
+	}
+	return uint64(stat.Dev) == w.rootDev
+}
@@ -0,0 +1,108 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFile creates path with n bytes of content, making parent directories
+// as needed.
+func writeFile(t *testing.T, path string, n int) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("MkdirAll(%s): %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, make([]byte, n), 0o644); err != nil {
+		t.Fatalf("WriteFile(%s): %v", path, err)
+	}
+}
+
+func TestWalkSumsRegularFiles(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "a.txt"), 10)
+	writeFile(t, filepath.Join(root, "sub", "b.txt"), 20)
+	writeFile(t, filepath.Join(root, "sub", "deeper", "c.txt"), 30)
+
+	got, err := Walk(root, Options{})
+	if err != nil {
+		t.Fatalf("Walk: unexpected error: %v", err)
+	}
+	if want := int64(60); got != want {
+		t.Errorf("Walk size = %d, want %d", got, want)
+	}
+}
+
+func TestWalkExcludesMatchBasename(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "keep.txt"), 10)
+	writeFile(t, filepath.Join(root, "drop.tmp"), 100)
+	writeFile(t, filepath.Join(root, "sub", "also-drop.tmp"), 100)
+
+	got, err := Walk(root, Options{Excludes: []string{"*.tmp"}})
+	if err != nil {
+		t.Fatalf("Walk: unexpected error: %v", err)
+	}
+	if want := int64(10); got != want {
+		t.Errorf("Walk size = %d, want %d (excludes should match by basename, not just full path)", got, want)
+	}
+}
+
+func TestWalkExcludesSkipDirectories(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "keep.txt"), 10)
+	writeFile(t, filepath.Join(root, "node_modules", "dep.js"), 100)
+
+	got, err := Walk(root, Options{Excludes: []string{"node_modules"}})
+	if err != nil {
+		t.Fatalf("Walk: unexpected error: %v", err)
+	}
+	if want := int64(10); got != want {
+		t.Errorf("Walk size = %d, want %d", got, want)
+	}
+}
+
+func TestWalkConcurrencyDoesNotChangeResult(t *testing.T) {
+	root := t.TempDir()
+	for i := 0; i < 50; i++ {
+		writeFile(t, filepath.Join(root, "d", string(rune('a'+i%26)), "f"+string(rune('0'+i%10))), i)
+	}
+
+	single, err := Walk(root, Options{Concurrency: 1})
+	if err != nil {
+		t.Fatalf("Walk(Concurrency: 1): unexpected error: %v", err)
+	}
+	concurrent, err := Walk(root, Options{Concurrency: 8})
+	if err != nil {
+		t.Fatalf("Walk(Concurrency: 8): unexpected error: %v", err)
+	}
+	if single != concurrent {
+		t.Errorf("Walk size depends on concurrency: single=%d concurrent=%d", single, concurrent)
+	}
+}
+
+func TestWalkReportsUnreadableDirectory(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("running as root: directory permissions aren't enforced")
+	}
+
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "keep.txt"), 10)
+	blocked := filepath.Join(root, "blocked")
+	if err := os.Mkdir(blocked, 0o755); err != nil {
+		t.Fatalf("Mkdir(%s): %v", blocked, err)
+	}
+	writeFile(t, filepath.Join(blocked, "hidden.txt"), 1000)
+	if err := os.Chmod(blocked, 0o000); err != nil {
+		t.Fatalf("Chmod(%s): %v", blocked, err)
+	}
+	defer os.Chmod(blocked, 0o755)
+
+	got, err := Walk(root, Options{})
+	if err == nil {
+		t.Fatal("Walk: expected an error for the unreadable directory, got nil")
+	}
+	if want := int64(10); got != want {
+		t.Errorf("Walk size = %d, want %d (should still count what it could read)", got, want)
+	}
+}